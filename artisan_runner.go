@@ -7,9 +7,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
-	"runtime"
 	"strconv"
 	"strings"
 	"time"
@@ -22,41 +20,63 @@ type AppConfig struct {
 	SavedAt        string `json:"saved_at"`
 }
 
-type Task struct {
-	Label string
-	Cmd   []string // e.g. []{"php", "artisan", "optimize:clear"}
-}
-
-var tasks = []Task{
-	{Label: "php artisan optimize:clear", Cmd: []string{"php", "artisan", "optimize:clear"}},
-	{Label: "php artisan config:clear", Cmd: []string{"php", "artisan", "config:clear"}},
-	{Label: "php artisan route:clear", Cmd: []string{"php", "artisan", "route:clear"}},
-	{Label: "php artisan cache:clear", Cmd: []string{"php", "artisan", "cache:clear"}},
-}
-
 func main() {
 	// Flags
 	projectPath := flag.String("path", ".", "Path to the Laravel project (where artisan lives)")
 	useLast := flag.Bool("use-last", false, "Run the last selections without prompting")
 	numbers := flag.String("numbers", "", "Comma-separated indices to run (1-based). Use 0 for all. Example: --numbers 1,3")
+	tag := flag.String("tag", "", "Only offer tasks carrying this tag, e.g. --tag deploy")
 	noColor := flag.Bool("no-color", false, "Disable colored output")
 	noSave := flag.Bool("no-save", false, "Do not remember this selection")
+	parallel := flag.Int("parallel", 1, "Number of tasks to run concurrently")
+	timeout := flag.Duration("timeout", 0, "Per-task timeout, e.g. 30s (0 = no timeout)")
+	failFast := flag.Bool("fail-fast", false, "Cancel remaining tasks on the first non-PASS result")
+	summaryJSON := flag.String("summary-json", "", "Write a machine-readable run summary to this path")
+	ciFlag := flag.Bool("ci", false, "Force non-interactive CI mode: no prompts, no colors, JSON log lines")
+	logFile := flag.String("log-file", "", "Tee JSON log lines to this path, independent of --ci")
+	dryRun := flag.Bool("dry-run", false, "Print the resolved execution order (with dependencies) and exit without running anything")
+	host := flag.String("host", "", "user@server[,user@server2,...] — run selected commands over SSH instead of locally")
+	remotePath := flag.String("remote-path", "", "Override where artisan lives on the remote host(s) (default: \".\")")
 	flag.Parse()
 
-	c := newColorizer(!*noColor)
+	ci := *ciFlag || !stdinIsTerminal()
+	c := newColorizer(!*noColor && !ci)
+	defer c.Restore()
+	logger, err := NewLogger(c, ci, *logFile)
+	if err != nil {
+		fail(c, err)
+	}
+	defer logger.Close()
 
 	// Resolve project path
 	absPath, err := filepath.Abs(*projectPath)
 	if err != nil {
-		fail(c, fmt.Errorf("unable to resolve path: %w", err))
+		logger.Fail(fmt.Errorf("unable to resolve path: %w", err))
 	}
-	info(c, fmt.Sprintf("Project path: %s", absPath))
+	logger.Info(fmt.Sprintf("Project path: %s", absPath))
 
 	// Validate artisan exists (best-effort)
 	if !fileExists(filepath.Join(absPath, "artisan")) {
-		warn(c, "artisan not found in the given path. If it lives elsewhere, commands may still work if PHP resolves it.")
+		logger.Warn("artisan not found in the given path. If it lives elsewhere, commands may still work if PHP resolves it.")
 	}
 
+	registry, err := LoadTaskRegistry(absPath)
+	if err != nil {
+		logger.Fail(fmt.Errorf("loading task registry: %w", err))
+	}
+	// Keep the full, unfiltered registry around for dependency resolution:
+	// --tag only narrows what's offered/selected below, not what DependsOn
+	// is allowed to pull in.
+	allTasks := registry.Tasks
+	registry = registry.FilterByTag(*tag)
+	if registry.Source != "" {
+		logger.Info(fmt.Sprintf("Loaded tasks from: %s", registry.Source))
+	}
+	if len(registry.Tasks) == 0 {
+		logger.Fail(errors.New("no tasks available (check --tag or your artisan-runner config)"))
+	}
+	tasks := registry.Tasks
+
 	// Determine selections
 	var selectedIdxs []int
 
@@ -67,7 +87,7 @@ func main() {
 		// numeric mode; support 0=all
 		idxs, err := parseNumbers(*numbers, len(tasks))
 		if err != nil {
-			fail(c, err)
+			logger.Fail(err)
 		}
 		selectedIdxs = idxs
 
@@ -75,13 +95,17 @@ func main() {
 		// load last
 		last, err := loadLastSelections(cfgPath)
 		if err != nil {
-			fail(c, fmt.Errorf("could not load last selections: %w", err))
+			logger.Fail(fmt.Errorf("could not load last selections: %w", err))
 		}
 		if len(last) == 0 {
-			fail(c, errors.New("no last selections saved"))
+			logger.Fail(errors.New("no last selections saved"))
 		}
 		selectedIdxs = last
 
+	case ci:
+		// CI mode disables the interactive prompt; callers must be explicit.
+		logger.Fail(errors.New("--ci requires --numbers or --use-last (no interactive prompt in CI mode)"))
+
 	default:
 		// Interactive TUI with checkboxes (+ All option)
 		opts := []string{"[Run ALL]"}
@@ -111,7 +135,7 @@ func main() {
 		}
 
 		if err := survey.AskOne(prompt, &picks, survey.WithValidator(survey.Required)); err != nil {
-			fail(c, err)
+			logger.Fail(err)
 		}
 
 		// translate picks
@@ -140,7 +164,7 @@ func main() {
 	}
 
 	if len(selectedIdxs) == 0 {
-		fail(c, errors.New("no commands selected"))
+		logger.Fail(errors.New("no commands selected"))
 	}
 
 	// Save selection unless disabled
@@ -148,33 +172,92 @@ func main() {
 		_ = saveLastSelections(cfgPath, selectedIdxs)
 	}
 
-	// Execute
-	ok(c, "Executing selected commands...\n")
-
+	// Resolve the selected tasks, preserving invalid-index warnings from
+	// before the parallel executor existed.
+	var selected []Task
 	for _, idx := range selectedIdxs {
-		// safe-guard
 		if idx < 1 || idx > len(tasks) {
-			warn(c, fmt.Sprintf("Skipping invalid index: %d", idx))
+			logger.Warn(fmt.Sprintf("Skipping invalid index: %d", idx))
 			continue
 		}
-		task := tasks[idx-1]
-		step(c, fmt.Sprintf("Running: %s", strings.Join(task.Cmd, " ")))
+		selected = append(selected, tasks[idx-1])
+	}
 
-		cmd := exec.Command(task.Cmd[0], task.Cmd[1:]...)
-		cmd.Dir = absPath
+	// Expand the selection to include transitive DependsOn prerequisites
+	// and put everything in a valid topological order. Resolve against the
+	// full registry so a tagged task can depend on an untagged one.
+	ordered, err := ResolveOrder(allTasks, selected)
+	if err != nil {
+		logger.Fail(err)
+	}
+	if len(ordered) > len(selected) {
+		logger.Info(fmt.Sprintf("Pulled in %d dependency task(s)", len(ordered)-len(selected)))
+	}
 
-		// stream output
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
+	hosts := parseHostsFlag(*host)
+	if len(hosts) == 0 {
+		hosts = registry.Hosts
+	}
 
-		if err := cmd.Run(); err != nil {
-			errMsg(c, fmt.Sprintf("Error running '%s': %v", task.Label, err))
-		} else {
-			ok(c, "Done\n")
+	if *dryRun {
+		logger.Ok("Resolved execution order (--dry-run, nothing executed):")
+		for i, t := range ordered {
+			fmt.Printf("  %d. %s\n", i+1, t.Label)
+		}
+		if len(hosts) > 0 {
+			logger.Info(fmt.Sprintf("Would run on hosts: %s", strings.Join(hosts, ", ")))
 		}
+		return
+	}
+
+	// Execute
+	logger.Ok("Executing selected commands...")
+
+	var results []TaskResult
+	if len(hosts) > 0 {
+		results = RunRemote(ordered, RemoteOptions{
+			Hosts:      hosts,
+			RemotePath: *remotePath,
+			Parallel:   *parallel,
+			Timeout:    *timeout,
+			FailFast:   *failFast,
+		}, logger)
+	} else {
+		results = RunDAG(ordered, ExecutorOptions{
+			Dir:      absPath,
+			Parallel: *parallel,
+			Timeout:  *timeout,
+			FailFast: *failFast,
+		}, logger)
 	}
 
-	ok(c, "All selected commands executed.")
+	if !ci {
+		PrintSummary(results, c)
+	}
+
+	if *summaryJSON != "" {
+		if err := writeSummaryJSON(*summaryJSON, results); err != nil {
+			logger.Error(fmt.Sprintf("writing -summary-json: %v", err))
+		}
+	}
+
+	logger.Ok("All selected commands executed.")
+	logger.Close()
+	c.Restore()
+
+	exitCode := CountFailed(results)
+	if exitCode > 125 {
+		exitCode = 125
+	}
+	os.Exit(exitCode)
+}
+
+func writeSummaryJSON(path string, results []TaskResult) error {
+	b, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
 }
 
 /* -------------------- helpers -------------------- */
@@ -263,47 +346,6 @@ func dedupe(in []int) []int {
 	return out
 }
 
-/* -------------------- coloring -------------------- */
-
-type colorizer struct {
-	enabled bool
-}
-
-func newColorizer(enabled bool) *colorizer { return &colorizer{enabled: enabled} }
-
-func (c *colorizer) wrap(code string, s string) string {
-	if !c.enabled {
-		return s
-	}
-	return code + s + "\x1b[0m"
-}
-func (c *colorizer) green(s string) string  { return c.wrap("\x1b[32m", s) }
-func (c *colorizer) yellow(s string) string { return c.wrap("\x1b[33m", s) }
-func (c *colorizer) red(s string) string    { return c.wrap("\x1b[31m", s) }
-func (c *colorizer) cyan(s string) string   { return c.wrap("\x1b[36m", s) }
-func (c *colorizer) bold(s string) string   { return c.wrap("\x1b[1m", s) }
-
-func info(c *colorizer, msg string)  { fmt.Println(c.cyan("ℹ "), msg) }
-func warn(c *colorizer, msg string)  { fmt.Println(c.yellow("⚠ "), msg) }
-func ok(c *colorizer, msg string)    { fmt.Println(c.green("✅ "), msg) }
-func step(c *colorizer, msg string)  { fmt.Println(c.bold("▶ "), msg) }
-func errMsg(c *colorizer, msg string){ fmt.Println(c.red("❌ "), msg) }
-func fail(c *colorizer, err error) {
-	errMsg(c, err.Error())
-	os.Exit(1)
-}
-
-/* -------------------- Windows ANSI enable (optional) -------------------- */
-
-// On Windows, you might need this to enable ANSI colors in legacy terminals.
-// It's safe to noop on other platforms.
-func init() {
-	if runtime.GOOS == "windows" {
-		// Best-effort: rely on modern terminals or VS Code integrated terminal.
-		// For full support, consider golang.org/x/sys/windows to enable VT processing.
-	}
-}
-
 /* -------------------- Numeric fallback prompt (unused, kept for extension) -------------------- */
 
 func numericPrompt(max int) []int {
@@ -318,4 +360,3 @@ func numericPrompt(max int) []int {
 	}
 	return idxs
 }
-
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Logger renders human-readable, colorized messages by default. In CI mode
+// (or when --log-file is set) it also emits structured JSON lines, one per
+// event, so pipelines like GitHub Actions or GitLab CI can consume them.
+type Logger struct {
+	color *colorizer
+	ci    bool
+	file  io.WriteCloser
+}
+
+// NewLogger builds a Logger. ci switches terminal output to JSON lines;
+// logFile, if non-empty, additionally tees every event as JSON to disk
+// regardless of ci.
+func NewLogger(color *colorizer, ci bool, logFile string) (*Logger, error) {
+	l := &Logger{color: color, ci: ci}
+	if logFile != "" {
+		f, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("opening -log-file: %w", err)
+		}
+		l.file = f
+	}
+	return l, nil
+}
+
+// Close releases the log file, if one was opened.
+func (l *Logger) Close() {
+	if l.file != nil {
+		_ = l.file.Close()
+	}
+}
+
+// emit marshals entry as one JSON line and writes it to -log-file (always)
+// and stdout (in --ci mode).
+func (l *Logger) emit(entry map[string]string) {
+	b, _ := json.Marshal(entry)
+	if l.file != nil {
+		fmt.Fprintln(l.file, string(b))
+	}
+	if l.ci {
+		fmt.Fprintln(os.Stdout, string(b))
+	}
+}
+
+// logLine emits a free-text human message under "message". Unlike
+// TaskEvent's "event", this field is not meant to be parsed as an enum.
+func (l *Logger) logLine(level, message string) {
+	l.emit(map[string]string{
+		"level":   level,
+		"message": message,
+		"ts":      time.Now().Format(time.RFC3339),
+	})
+}
+
+func (l *Logger) Info(msg string) {
+	l.logLine("info", msg)
+	if !l.ci {
+		info(l.color, msg)
+	}
+}
+
+func (l *Logger) Warn(msg string) {
+	l.logLine("warn", msg)
+	if !l.ci {
+		warn(l.color, msg)
+	}
+}
+
+func (l *Logger) Ok(msg string) {
+	l.logLine("ok", msg)
+	if !l.ci {
+		ok(l.color, msg)
+	}
+}
+
+func (l *Logger) Step(msg string) {
+	l.logLine("step", msg)
+	if !l.ci {
+		step(l.color, msg)
+	}
+}
+
+func (l *Logger) Error(msg string) {
+	l.logLine("error", msg)
+	if !l.ci {
+		errMsg(l.color, msg)
+	}
+}
+
+// Fail logs err and exits the process with status 1.
+func (l *Logger) Fail(err error) {
+	l.Error(err.Error())
+	l.Close()
+	l.color.Restore()
+	os.Exit(1)
+}
+
+// TaskEvent logs a lifecycle event for a single task under the
+// machine-parseable "event" field, e.g. "start" or
+// "pass"/"fail"/"timeout"/"skipped".
+func (l *Logger) TaskEvent(task, event string) {
+	l.emit(map[string]string{
+		"level": "info",
+		"event": event,
+		"task":  task,
+		"ts":    time.Now().Format(time.RFC3339),
+	})
+}
+
+// stdinIsTerminal reports whether stdin is attached to a terminal, used to
+// auto-detect CI mode when --ci isn't passed explicitly.
+func stdinIsTerminal() bool {
+	fi, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return (fi.Mode() & os.ModeCharDevice) != 0
+}
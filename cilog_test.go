@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// nopWriteCloser adapts a bytes.Buffer to io.WriteCloser for tests, since
+// Logger.file only needs Write/Close.
+type nopWriteCloser struct {
+	*bytes.Buffer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+func newTestLogger() (*Logger, *bytes.Buffer) {
+	buf := &bytes.Buffer{}
+	return &Logger{ci: true, file: nopWriteCloser{buf}}, buf
+}
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", line, err)
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+func TestLoggerInfoUsesMessageNotEvent(t *testing.T) {
+	logger, buf := newTestLogger()
+	logger.Info("Project path: /tmp/x")
+
+	entries := decodeLines(t, buf)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 log line, got %d", len(entries))
+	}
+	if _, ok := entries[0]["event"]; ok {
+		t.Errorf("expected no \"event\" field on a free-text log, got %v", entries[0])
+	}
+	if entries[0]["message"] != "Project path: /tmp/x" {
+		t.Errorf("expected message field to carry the text, got %v", entries[0])
+	}
+}
+
+func TestLoggerTaskEventUsesEventNotMessage(t *testing.T) {
+	logger, buf := newTestLogger()
+	logger.TaskEvent("config:clear", "start")
+
+	entries := decodeLines(t, buf)
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 log line, got %d", len(entries))
+	}
+	if entries[0]["event"] != "start" {
+		t.Errorf("expected event=start, got %v", entries[0])
+	}
+	if entries[0]["task"] != "config:clear" {
+		t.Errorf("expected task=config:clear, got %v", entries[0])
+	}
+	if _, ok := entries[0]["message"]; ok {
+		t.Errorf("expected no \"message\" field on a task event, got %v", entries[0])
+	}
+}
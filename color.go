@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+)
+
+// colorizer wraps strings in ANSI escape codes, modeled after the
+// fatih/color style of named, self-contained color methods. A single
+// instance is shared across the process; on Windows it also owns the
+// console-mode change needed to make those escapes render.
+type colorizer struct {
+	enabled bool
+	restore func()
+}
+
+// newColorizer builds a colorizer. want reflects the user's own intent
+// (the inverse of --no-color); NO_COLOR, CLICOLOR_FORCE and TERM=dumb can
+// each override it, and on Windows the legacy console is probed and, if
+// necessary, switched into VT-processing mode.
+func newColorizer(want bool) *colorizer {
+	c := &colorizer{enabled: resolveColorEnabled(want)}
+	if c.enabled && runtime.GOOS == "windows" {
+		restore, ok := enableWindowsVT()
+		if !ok {
+			c.enabled = false
+		} else {
+			c.restore = restore
+		}
+	}
+	return c
+}
+
+// resolveColorEnabled applies the standard NO_COLOR / CLICOLOR_FORCE / dumb
+// terminal conventions on top of the caller's preference.
+func resolveColorEnabled(want bool) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if v := os.Getenv("CLICOLOR_FORCE"); v != "" && v != "0" {
+		return true
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return want
+}
+
+// Restore undoes any console-mode change made for this colorizer. Safe to
+// call on non-Windows platforms or when nothing was changed.
+func (c *colorizer) Restore() {
+	if c.restore != nil {
+		c.restore()
+	}
+}
+
+func (c *colorizer) wrap(code string, s string) string {
+	if !c.enabled {
+		return s
+	}
+	return code + s + "\x1b[0m"
+}
+
+func (c *colorizer) Green(s string) string  { return c.wrap("\x1b[32m", s) }
+func (c *colorizer) Yellow(s string) string { return c.wrap("\x1b[33m", s) }
+func (c *colorizer) Red(s string) string    { return c.wrap("\x1b[31m", s) }
+func (c *colorizer) Cyan(s string) string   { return c.wrap("\x1b[36m", s) }
+func (c *colorizer) Bold(s string) string   { return c.wrap("\x1b[1m", s) }
+
+func info(c *colorizer, msg string)   { fmt.Println(c.Cyan("ℹ "), msg) }
+func warn(c *colorizer, msg string)   { fmt.Println(c.Yellow("⚠ "), msg) }
+func ok(c *colorizer, msg string)     { fmt.Println(c.Green("✅ "), msg) }
+func step(c *colorizer, msg string)   { fmt.Println(c.Bold("▶ "), msg) }
+func errMsg(c *colorizer, msg string) { fmt.Println(c.Red("❌ "), msg) }
+func fail(c *colorizer, err error) {
+	errMsg(c, err.Error())
+	c.Restore()
+	os.Exit(1)
+}
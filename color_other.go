@@ -0,0 +1,9 @@
+//go:build !windows
+
+package main
+
+// enableWindowsVT is a no-op off Windows: Linux, macOS and Windows'
+// ConPTY-backed terminals all honor ANSI escapes natively.
+func enableWindowsVT() (restore func(), ok bool) {
+	return nil, true
+}
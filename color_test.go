@@ -0,0 +1,34 @@
+package main
+
+import "testing"
+
+func TestResolveColorEnabled(t *testing.T) {
+	cases := []struct {
+		name          string
+		want          bool
+		noColor       string
+		clicolorForce string
+		term          string
+		expect        bool
+	}{
+		{name: "respects want when no overrides", want: true, expect: true},
+		{name: "respects want=false when no overrides", want: false, expect: false},
+		{name: "NO_COLOR wins over want=true", want: true, noColor: "1", expect: false},
+		{name: "CLICOLOR_FORCE wins over want=false", want: false, clicolorForce: "1", expect: true},
+		{name: "CLICOLOR_FORCE=0 does not force", want: false, clicolorForce: "0", expect: false},
+		{name: "TERM=dumb wins over want=true", want: true, term: "dumb", expect: false},
+		{name: "NO_COLOR takes precedence over CLICOLOR_FORCE", want: false, noColor: "1", clicolorForce: "1", expect: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", c.noColor)
+			t.Setenv("CLICOLOR_FORCE", c.clicolorForce)
+			t.Setenv("TERM", c.term)
+
+			if got := resolveColorEnabled(c.want); got != c.expect {
+				t.Errorf("resolveColorEnabled(%v) = %v, want %v", c.want, got, c.expect)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// enableWindowsVT flips ENABLE_VIRTUAL_TERMINAL_PROCESSING on stdout's
+// console handle so ANSI escapes render on Windows Terminal and legacy
+// cmd.exe alike. It reports ok=false (leaving the caller to fall back to
+// plain text) when stdout isn't a real console, e.g. when piped or
+// redirected to a file.
+func enableWindowsVT() (restore func(), ok bool) {
+	handle := windows.Handle(os.Stdout.Fd())
+
+	var original uint32
+	if err := windows.GetConsoleMode(handle, &original); err != nil {
+		return nil, false
+	}
+
+	if err := windows.SetConsoleMode(handle, original|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING); err != nil {
+		return nil, false
+	}
+
+	return func() {
+		_ = windows.SetConsoleMode(handle, original)
+	}, true
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// CycleError is returned by ResolveOrder when a task's DependsOn chain
+// loops back on itself.
+type CycleError struct {
+	Nodes []string
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("dependency cycle detected among tasks: %s", strings.Join(e.Nodes, ", "))
+}
+
+// buildGraph computes in-degree counts and successor lists for nodes, keyed
+// by Task.Label, as used by Kahn's algorithm.
+func buildGraph(nodes []Task) (indegree map[string]int, successors map[string][]string) {
+	indegree = make(map[string]int, len(nodes))
+	successors = make(map[string][]string)
+	for _, n := range nodes {
+		indegree[n.Label] = 0
+	}
+	for _, n := range nodes {
+		for _, dep := range n.DependsOn {
+			successors[dep] = append(successors[dep], n.Label)
+			indegree[n.Label]++
+		}
+	}
+	return indegree, successors
+}
+
+// ResolveOrder expands selected to include its transitive DependsOn
+// prerequisites (looked up in all) and returns the full set in a valid
+// topological order. It returns a *CycleError if the dependencies loop.
+func ResolveOrder(all []Task, selected []Task) ([]Task, error) {
+	byLabel := make(map[string]Task, len(all))
+	for _, t := range all {
+		byLabel[t.Label] = t
+	}
+
+	included := map[string]bool{}
+	var expand func(label string) error
+	expand = func(label string) error {
+		if included[label] {
+			return nil
+		}
+		t, ok := byLabel[label]
+		if !ok {
+			return fmt.Errorf("unknown task in dependency graph: %q", label)
+		}
+		included[label] = true
+		for _, dep := range t.DependsOn {
+			if _, ok := byLabel[dep]; !ok {
+				return fmt.Errorf("task %q depends on unknown task %q", label, dep)
+			}
+			if err := expand(dep); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, t := range selected {
+		if err := expand(t.Label); err != nil {
+			return nil, err
+		}
+	}
+
+	// Preserve the registry's own ordering among included nodes, so the
+	// topological sort below is deterministic.
+	var nodes []Task
+	for _, t := range all {
+		if included[t.Label] {
+			nodes = append(nodes, t)
+		}
+	}
+
+	indegree, successors := buildGraph(nodes)
+
+	var queue []string
+	for _, n := range nodes {
+		if indegree[n.Label] == 0 {
+			queue = append(queue, n.Label)
+		}
+	}
+
+	ordered := make([]Task, 0, len(nodes))
+	for len(queue) > 0 {
+		label := queue[0]
+		queue = queue[1:]
+		ordered = append(ordered, byLabel[label])
+		for _, succ := range successors[label] {
+			indegree[succ]--
+			if indegree[succ] == 0 {
+				queue = append(queue, succ)
+			}
+		}
+	}
+
+	if len(ordered) < len(nodes) {
+		var cyclic []string
+		for _, n := range nodes {
+			if indegree[n.Label] > 0 {
+				cyclic = append(cyclic, n.Label)
+			}
+		}
+		return nil, &CycleError{Nodes: cyclic}
+	}
+
+	return ordered, nil
+}
+
+// RunDAG executes nodes through the same bounded worker pool as RunTasks,
+// but releases each task to the pool only once its DependsOn prerequisites
+// have all completed, so independent branches still run concurrently.
+// nodes must already be free of cycles (see ResolveOrder).
+func RunDAG(nodes []Task, opts ExecutorOptions, logger *Logger) []TaskResult {
+	parallel := opts.Parallel
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	byLabel := make(map[string]Task, len(nodes))
+	for _, n := range nodes {
+		byLabel[n.Label] = n
+	}
+	indegree, successors := buildGraph(nodes)
+
+	var mu sync.Mutex
+	resultsByLabel := make(map[string]TaskResult, len(nodes))
+
+	sem := make(chan struct{}, parallel)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var wg sync.WaitGroup
+	var failed int32
+	var cancelOnce sync.Once
+
+	var launch func(label string)
+	launch = func(label string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			// Acquire the pool slot inside the goroutine: launch can be called
+			// by a task's own goroutine once it finishes (to release its
+			// successors), and that goroutine may not have freed its slot yet.
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			var res TaskResult
+			if opts.FailFast && atomic.LoadInt32(&failed) > 0 {
+				res = TaskResult{Label: label, Status: StatusSkipped}
+			} else {
+				res = runOneTask(ctx, byLabel[label], opts, logger)
+				if res.Status == StatusFail || res.Status == StatusTimeout {
+					atomic.AddInt32(&failed, 1)
+					if opts.FailFast {
+						cancelOnce.Do(cancel)
+					}
+				}
+			}
+
+			mu.Lock()
+			resultsByLabel[label] = res
+			var ready []string
+			for _, succ := range successors[label] {
+				indegree[succ]--
+				if indegree[succ] == 0 {
+					ready = append(ready, succ)
+				}
+			}
+			mu.Unlock()
+
+			for _, r := range ready {
+				launch(r)
+			}
+		}()
+	}
+
+	// Collect the initial zero-indegree labels before launching any of
+	// them: once launch starts a goroutine, it mutates indegree under mu,
+	// so reading the map here while that's happening would race.
+	var roots []string
+	for _, n := range nodes {
+		if indegree[n.Label] == 0 {
+			roots = append(roots, n.Label)
+		}
+	}
+	for _, label := range roots {
+		launch(label)
+	}
+
+	wg.Wait()
+
+	results := make([]TaskResult, len(nodes))
+	for i, n := range nodes {
+		results[i] = resultsByLabel[n.Label]
+	}
+	return results
+}
@@ -0,0 +1,78 @@
+package main
+
+import "testing"
+
+func labels(tasks []Task) []string {
+	out := make([]string, len(tasks))
+	for i, t := range tasks {
+		out[i] = t.Label
+	}
+	return out
+}
+
+func TestResolveOrderPullsInUntaggedDependency(t *testing.T) {
+	// Regression test: a tagged task depending on an untagged one must still
+	// resolve when the caller only selected the tagged task, as long as
+	// ResolveOrder is given the full registry (not a tag-filtered subset).
+	all := []Task{
+		{Label: "config:clear", Cmd: "php"},
+		{Label: "route:cache", Cmd: "php", Tags: []string{"deploy"}, DependsOn: []string{"config:clear"}},
+	}
+	selected := []Task{all[1]}
+
+	ordered, err := ResolveOrder(all, selected)
+	if err != nil {
+		t.Fatalf("ResolveOrder returned error: %v", err)
+	}
+	if got := labels(ordered); len(got) != 2 || got[0] != "config:clear" || got[1] != "route:cache" {
+		t.Fatalf("expected [config:clear route:cache], got %v", got)
+	}
+}
+
+func TestResolveOrderDetectsCycle(t *testing.T) {
+	all := []Task{
+		{Label: "a", DependsOn: []string{"b"}},
+		{Label: "b", DependsOn: []string{"a"}},
+	}
+
+	_, err := ResolveOrder(all, all)
+	if err == nil {
+		t.Fatal("expected a CycleError, got nil")
+	}
+	if _, ok := err.(*CycleError); !ok {
+		t.Fatalf("expected *CycleError, got %T: %v", err, err)
+	}
+}
+
+func TestRunDAGConcurrentRoots(t *testing.T) {
+	// Regression test for a data race (caught by `go test -race`) where the
+	// initial zero-indegree scan read the shared indegree map while earlier
+	// roots' goroutines were already mutating it. Several independent roots
+	// ensures launch fires concurrently for more than one label.
+	nodes := []Task{
+		{Label: "a", Cmd: "true"},
+		{Label: "b", Cmd: "true"},
+		{Label: "c", Cmd: "true", DependsOn: []string{"a"}},
+	}
+
+	results := RunDAG(nodes, ExecutorOptions{Parallel: 4}, &Logger{ci: true})
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	for _, r := range results {
+		if r.Status != StatusPass {
+			t.Errorf("task %q: expected PASS, got %s", r.Label, r.Status)
+		}
+	}
+}
+
+func TestResolveOrderUnknownDependency(t *testing.T) {
+	all := []Task{
+		{Label: "a", DependsOn: []string{"missing"}},
+	}
+
+	_, err := ResolveOrder(all, all)
+	if err == nil {
+		t.Fatal("expected an error for an unknown dependency, got nil")
+	}
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TaskStatus is the terminal state of a single task run.
+type TaskStatus string
+
+const (
+	StatusPass    TaskStatus = "PASS"
+	StatusFail    TaskStatus = "FAIL"
+	StatusTimeout TaskStatus = "TIMEOUT"
+	StatusSkipped TaskStatus = "SKIPPED"
+)
+
+// TaskResult records everything about one task execution, for the summary
+// table and for -summary-json consumers.
+type TaskResult struct {
+	Host     string        `json:"host,omitempty"`
+	Label    string        `json:"label"`
+	Command  string        `json:"command"`
+	Status   TaskStatus    `json:"status"`
+	ExitCode int           `json:"exit_code"`
+	Start    time.Time     `json:"start"`
+	End      time.Time     `json:"end"`
+	Duration time.Duration `json:"-"`
+	Output   string        `json:"output,omitempty"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// MarshalJSON emits Duration as duration_ms, i.e. whole milliseconds,
+// instead of time.Duration's default nanosecond encoding.
+func (r TaskResult) MarshalJSON() ([]byte, error) {
+	type alias TaskResult
+	return json.Marshal(struct {
+		alias
+		DurationMS int64 `json:"duration_ms"`
+	}{
+		alias:      alias(r),
+		DurationMS: r.Duration.Milliseconds(),
+	})
+}
+
+// ExecutorOptions configures a RunTasks call.
+type ExecutorOptions struct {
+	Dir      string
+	Parallel int
+	Timeout  time.Duration // 0 means no per-task timeout
+	FailFast bool
+}
+
+// RunTasks drives tasks through a bounded worker pool, honoring per-task
+// timeouts and, when FailFast is set, cancelling not-yet-started work after
+// the first non-PASS result.
+func RunTasks(tasks []Task, opts ExecutorOptions, logger *Logger) []TaskResult {
+	parallel := opts.Parallel
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	results := make([]TaskResult, len(tasks))
+	sem := make(chan struct{}, parallel)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var failed int32
+	var cancelOnce sync.Once
+
+	for i, task := range tasks {
+		if opts.FailFast && atomic.LoadInt32(&failed) > 0 {
+			results[i] = TaskResult{Label: task.Label, Command: strings.Join(task.FullCommand(), " "), Status: StatusSkipped}
+			continue
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, task Task) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			res := runOneTask(ctx, task, opts, logger)
+			results[i] = res
+			if res.Status == StatusFail || res.Status == StatusTimeout {
+				atomic.AddInt32(&failed, 1)
+				if opts.FailFast {
+					cancelOnce.Do(cancel)
+				}
+			}
+		}(i, task)
+	}
+
+	wg.Wait()
+	return results
+}
+
+func runOneTask(ctx context.Context, task Task, opts ExecutorOptions, logger *Logger) TaskResult {
+	full := task.FullCommand()
+	res := TaskResult{Label: task.Label, Command: strings.Join(full, " "), Start: time.Now()}
+	logger.TaskEvent(task.Label, "start")
+	defer func() { logger.TaskEvent(task.Label, strings.ToLower(string(res.Status))) }()
+
+	applicable, err := task.Applicable()
+	if err != nil {
+		res.End = time.Now()
+		res.Duration = res.End.Sub(res.Start)
+		res.Status = StatusFail
+		res.Error = err.Error()
+		return res
+	}
+	if !applicable {
+		res.End = res.Start
+		res.Status = StatusSkipped
+		return res
+	}
+
+	runCtx := ctx
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(runCtx, full[0], full[1:]...)
+	cmd.Dir = opts.Dir
+	if task.Cwd != "" {
+		cmd.Dir = filepath.Join(opts.Dir, task.Cwd)
+	}
+	if len(task.Env) > 0 {
+		cmd.Env = os.Environ()
+		for k, v := range task.Env {
+			cmd.Env = append(cmd.Env, k+"="+v)
+		}
+	}
+
+	var buf bytes.Buffer
+	prefixed := newPrefixWriter(os.Stdout, fmt.Sprintf("[%s] ", task.Label))
+	out := io.MultiWriter(prefixed, &buf)
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	runErr := cmd.Run()
+	prefixed.Flush()
+
+	res.End = time.Now()
+	res.Duration = res.End.Sub(res.Start)
+	res.Output = buf.String()
+
+	switch {
+	case runCtx.Err() == context.DeadlineExceeded:
+		res.Status = StatusTimeout
+		res.ExitCode = -1
+		res.Error = fmt.Sprintf("timed out after %s", opts.Timeout)
+	case runErr != nil:
+		res.Status = StatusFail
+		res.Error = runErr.Error()
+		if exitErr, ok := runErr.(*exec.ExitError); ok {
+			res.ExitCode = exitErr.ExitCode()
+		} else {
+			res.ExitCode = -1
+		}
+	default:
+		res.Status = StatusPass
+	}
+	return res
+}
+
+// prefixWriter prefixes every line written to it with a label, so concurrent
+// tasks can stream live output without interleaving mid-line.
+type prefixWriter struct {
+	mu     sync.Mutex
+	out    io.Writer
+	prefix string
+	buf    []byte
+}
+
+func newPrefixWriter(out io.Writer, prefix string) *prefixWriter {
+	return &prefixWriter{out: out, prefix: prefix}
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		fmt.Fprintf(w.out, "%s%s\n", w.prefix, w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush writes out any trailing partial line left without a terminating
+// newline, so output isn't silently dropped.
+func (w *prefixWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) > 0 {
+		fmt.Fprintf(w.out, "%s%s\n", w.prefix, w.buf)
+		w.buf = nil
+	}
+}
+
+// PrintSummary renders the PASS/FAIL/TIMEOUT table shown after a run.
+func PrintSummary(results []TaskResult, c *colorizer) {
+	fmt.Println()
+	fmt.Println(c.Bold("Summary:"))
+	for _, r := range results {
+		var label string
+		switch r.Status {
+		case StatusPass:
+			label = c.Green(string(r.Status))
+		case StatusTimeout:
+			label = c.Yellow(string(r.Status))
+		case StatusSkipped:
+			label = c.Yellow(string(r.Status))
+		default:
+			label = c.Red(string(r.Status))
+		}
+		name := r.Label
+		if r.Host != "" {
+			name = fmt.Sprintf("[%s] %s", r.Host, r.Label)
+		}
+		fmt.Printf("  %-8s %-40s %s\n", label, name, r.Duration.Round(time.Millisecond))
+	}
+}
+
+// CountFailed returns the number of results that actually failed, i.e. FAIL
+// or TIMEOUT. SKIPPED does not count: it also covers tasks whose When
+// predicate legitimately didn't match, and pre-empted fail-fast work, neither
+// of which should show up in the aggregate exit code.
+func CountFailed(results []TaskResult) int {
+	n := 0
+	for _, r := range results {
+		if r.Status == StatusFail || r.Status == StatusTimeout {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestRunTasksTimeout(t *testing.T) {
+	tasks := []Task{
+		{Label: "slow", Cmd: "sleep", Args: []string{"1"}},
+	}
+
+	results := RunTasks(tasks, ExecutorOptions{Parallel: 1, Timeout: 50 * time.Millisecond}, &Logger{ci: true})
+
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	if results[0].Status != StatusTimeout {
+		t.Fatalf("expected TIMEOUT, got %s", results[0].Status)
+	}
+}
+
+func TestRunTasksRespectsParallelLimit(t *testing.T) {
+	// Five tasks, pool of 2: confirms the semaphore actually bounds
+	// concurrency rather than just happening to work for Parallel: 1.
+	tasks := make([]Task, 5)
+	for i := range tasks {
+		tasks[i] = Task{Label: "t", Cmd: "true"}
+	}
+
+	results := RunTasks(tasks, ExecutorOptions{Parallel: 2}, &Logger{ci: true})
+
+	if len(results) != 5 {
+		t.Fatalf("expected 5 results, got %d", len(results))
+	}
+	for _, r := range results {
+		if r.Status != StatusPass {
+			t.Errorf("expected PASS, got %s", r.Status)
+		}
+	}
+}
+
+func TestTaskResultMarshalJSONDurationInMilliseconds(t *testing.T) {
+	r := TaskResult{Label: "a", Status: StatusPass, Duration: 254 * time.Millisecond}
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var out map[string]any
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	got, ok := out["duration_ms"].(float64)
+	if !ok {
+		t.Fatalf("duration_ms missing or not a number: %v", out)
+	}
+	if got != 254 {
+		t.Errorf("expected duration_ms=254, got %v", got)
+	}
+}
+
+func TestCountFailedIgnoresSkipped(t *testing.T) {
+	results := []TaskResult{
+		{Label: "a", Status: StatusPass},
+		{Label: "b", Status: StatusSkipped},
+		{Label: "c", Status: StatusFail},
+		{Label: "d", Status: StatusTimeout},
+		{Label: "e", Status: StatusSkipped},
+	}
+
+	if n := CountFailed(results); n != 2 {
+		t.Fatalf("expected 2 failures (FAIL + TIMEOUT), got %d", n)
+	}
+}
+
+func TestRunTasksWhenFalseDoesNotCountAsFailure(t *testing.T) {
+	// Regression test: a task whose When predicate legitimately doesn't
+	// match must finish SKIPPED without being counted as a failure or
+	// tripping fail-fast cancellation of the rest of the run.
+	tasks := []Task{
+		{Label: "always", Cmd: "true"},
+		{Label: "conditional", Cmd: "true", When: "false"},
+		{Label: "also-always", Cmd: "true"},
+	}
+
+	results := RunTasks(tasks, ExecutorOptions{Parallel: 1, FailFast: true}, &Logger{ci: true})
+
+	if n := CountFailed(results); n != 0 {
+		t.Fatalf("expected 0 failures, got %d: %+v", n, results)
+	}
+	for _, r := range results {
+		if r.Label == "conditional" && r.Status != StatusSkipped {
+			t.Errorf("expected conditional task to be SKIPPED, got %s", r.Status)
+		}
+		if r.Label != "conditional" && r.Status != StatusPass {
+			t.Errorf("task %q: expected PASS, got %s", r.Label, r.Status)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Task describes a single runnable artisan command, loaded either from the
+// built-in defaults or from a user-supplied artisan-runner.yaml/.toml file.
+type Task struct {
+	Label     string            `yaml:"label" toml:"label"`
+	Cmd       string            `yaml:"cmd" toml:"cmd"`
+	Args      []string          `yaml:"args" toml:"args"`
+	Cwd       string            `yaml:"cwd" toml:"cwd"`
+	Env       map[string]string `yaml:"env" toml:"env"`
+	When      string            `yaml:"when" toml:"when"`
+	Tags      []string          `yaml:"tags" toml:"tags"`
+	DependsOn []string          `yaml:"depends_on" toml:"depends_on"`
+}
+
+// FullCommand returns the command and its arguments as a single slice,
+// suitable for exec.Command and for display purposes.
+func (t Task) FullCommand() []string {
+	return append([]string{t.Cmd}, t.Args...)
+}
+
+// Applicable runs t.When (if set) through the platform shell and reports
+// whether the task should run on this host. An empty predicate always
+// applies. A predicate that exits non-zero means "skip"; any other
+// execution error is surfaced so the caller can decide how to report it.
+func (t Task) Applicable() (bool, error) {
+	if strings.TrimSpace(t.When) == "" {
+		return true, nil
+	}
+	cmd := whenCommand(t.When)
+	err := cmd.Run()
+	if err == nil {
+		return true, nil
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return false, nil
+	}
+	return false, fmt.Errorf("evaluating predicate %q: %w", t.When, err)
+}
+
+// taskRegistryFile is the on-disk shape of artisan-runner.yaml/.toml.
+type taskRegistryFile struct {
+	Tasks []Task   `yaml:"tasks" toml:"tasks"`
+	Hosts []string `yaml:"hosts" toml:"hosts"`
+}
+
+// TaskRegistry holds the resolved set of tasks a run can select from.
+type TaskRegistry struct {
+	Tasks  []Task
+	Hosts  []string // default --host targets from the config file, e.g. ["deploy@web-1"]
+	Source string   // path the tasks were loaded from, or "" for built-in defaults
+}
+
+// defaultTasks preserves the four commands artisan-runner shipped with
+// before the registry existed, for projects with no config file.
+func defaultTasks() []Task {
+	return []Task{
+		{Label: "php artisan optimize:clear", Cmd: "php", Args: []string{"artisan", "optimize:clear"}},
+		{Label: "php artisan config:clear", Cmd: "php", Args: []string{"artisan", "config:clear"}},
+		{Label: "php artisan route:clear", Cmd: "php", Args: []string{"artisan", "route:clear"}},
+		{Label: "php artisan cache:clear", Cmd: "php", Args: []string{"artisan", "cache:clear"}},
+	}
+}
+
+// LoadTaskRegistry resolves the task list for a run: it first looks for
+// artisan-runner.yaml/.yml/.toml at the project root, then for the same
+// names under os.UserConfigDir()/artisan-runner, and finally falls back to
+// the built-in defaults.
+func LoadTaskRegistry(projectPath string) (*TaskRegistry, error) {
+	var candidates []string
+	for _, name := range []string{"artisan-runner.yaml", "artisan-runner.yml", "artisan-runner.toml"} {
+		candidates = append(candidates, filepath.Join(projectPath, name))
+	}
+	if dir, err := os.UserConfigDir(); err == nil {
+		for _, name := range []string{"artisan-runner.yaml", "artisan-runner.yml", "artisan-runner.toml"} {
+			candidates = append(candidates, filepath.Join(dir, "artisan-runner", name))
+		}
+	}
+
+	for _, path := range candidates {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var file taskRegistryFile
+		if strings.HasSuffix(path, ".toml") {
+			if _, err := toml.Decode(string(b), &file); err != nil {
+				return nil, fmt.Errorf("parsing %s: %w", path, err)
+			}
+		} else {
+			if err := yaml.Unmarshal(b, &file); err != nil {
+				return nil, fmt.Errorf("parsing %s: %w", path, err)
+			}
+		}
+		if len(file.Tasks) == 0 {
+			continue
+		}
+		return &TaskRegistry{Tasks: file.Tasks, Hosts: file.Hosts, Source: path}, nil
+	}
+
+	return &TaskRegistry{Tasks: defaultTasks()}, nil
+}
+
+// FilterByTag returns the subset of tasks carrying the given tag. An empty
+// tag returns the registry unchanged.
+func (r *TaskRegistry) FilterByTag(tag string) *TaskRegistry {
+	if tag == "" {
+		return r
+	}
+	filtered := &TaskRegistry{Source: r.Source, Hosts: r.Hosts}
+	for _, t := range r.Tasks {
+		for _, tg := range t.Tags {
+			if tg == tag {
+				filtered.Tasks = append(filtered.Tasks, t)
+				break
+			}
+		}
+	}
+	return filtered
+}
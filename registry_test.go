@@ -0,0 +1,84 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadTaskRegistryYAML(t *testing.T) {
+	dir := t.TempDir()
+	yaml := `
+tasks:
+  - label: config:clear
+    cmd: php
+    args: [artisan, config:clear]
+hosts:
+  - deploy@web-1
+`
+	if err := os.WriteFile(filepath.Join(dir, "artisan-runner.yaml"), []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	reg, err := LoadTaskRegistry(dir)
+	if err != nil {
+		t.Fatalf("LoadTaskRegistry: %v", err)
+	}
+	if len(reg.Tasks) != 1 || reg.Tasks[0].Label != "config:clear" {
+		t.Fatalf("unexpected tasks: %+v", reg.Tasks)
+	}
+	if len(reg.Hosts) != 1 || reg.Hosts[0] != "deploy@web-1" {
+		t.Fatalf("unexpected hosts: %+v", reg.Hosts)
+	}
+}
+
+func TestLoadTaskRegistryTOML(t *testing.T) {
+	dir := t.TempDir()
+	toml := `
+[[tasks]]
+label = "cache:clear"
+cmd = "php"
+args = ["artisan", "cache:clear"]
+`
+	if err := os.WriteFile(filepath.Join(dir, "artisan-runner.toml"), []byte(toml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	reg, err := LoadTaskRegistry(dir)
+	if err != nil {
+		t.Fatalf("LoadTaskRegistry: %v", err)
+	}
+	if len(reg.Tasks) != 1 || reg.Tasks[0].Label != "cache:clear" {
+		t.Fatalf("unexpected tasks: %+v", reg.Tasks)
+	}
+}
+
+func TestLoadTaskRegistryFallsBackToDefaults(t *testing.T) {
+	reg, err := LoadTaskRegistry(t.TempDir())
+	if err != nil {
+		t.Fatalf("LoadTaskRegistry: %v", err)
+	}
+	if len(reg.Tasks) != len(defaultTasks()) {
+		t.Fatalf("expected %d default tasks, got %d", len(defaultTasks()), len(reg.Tasks))
+	}
+	if reg.Source != "" {
+		t.Errorf("expected empty Source for defaults, got %q", reg.Source)
+	}
+}
+
+func TestFilterByTag(t *testing.T) {
+	reg := &TaskRegistry{Tasks: []Task{
+		{Label: "a", Tags: []string{"deploy"}},
+		{Label: "b", Tags: []string{"local"}},
+		{Label: "c", Tags: []string{"deploy", "local"}},
+	}}
+
+	filtered := reg.FilterByTag("deploy")
+	if len(filtered.Tasks) != 2 {
+		t.Fatalf("expected 2 tasks tagged deploy, got %d: %+v", len(filtered.Tasks), filtered.Tasks)
+	}
+
+	if unfiltered := reg.FilterByTag(""); len(unfiltered.Tasks) != 3 {
+		t.Fatalf("expected FilterByTag(\"\") to return all tasks, got %d", len(unfiltered.Tasks))
+	}
+}
@@ -0,0 +1,11 @@
+//go:build windows
+
+package main
+
+import "os/exec"
+
+// whenCommand builds the command used to evaluate a Task.When predicate on
+// Windows, where there's no sh on PATH by default.
+func whenCommand(expr string) *exec.Cmd {
+	return exec.Command("cmd", "/C", expr)
+}
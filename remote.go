@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// RemoteOptions configures a RunRemote call.
+type RemoteOptions struct {
+	Hosts      []string // "user@server[:port]" entries
+	RemotePath string   // override for where artisan lives; defaults to "."
+	Parallel   int      // max hosts driven concurrently
+	Timeout    time.Duration
+	FailFast   bool
+}
+
+// parseHostsFlag splits a --host user@server[,user@server2] value.
+func parseHostsFlag(s string) []string {
+	var out []string
+	for _, h := range strings.Split(s, ",") {
+		h = strings.TrimSpace(h)
+		if h != "" {
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
+// RunRemote runs tasks, in order, against each host over SSH. Hosts are
+// fanned out across the same bounded worker pool the local executor uses;
+// tasks within a single host run sequentially so DependsOn ordering holds.
+func RunRemote(tasks []Task, opts RemoteOptions, logger *Logger) []TaskResult {
+	parallel := opts.Parallel
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	var mu sync.Mutex
+	var results []TaskResult
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+
+	for _, hostSpec := range opts.Hosts {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(hostSpec string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			hostResults := runHost(hostSpec, tasks, opts, logger)
+			mu.Lock()
+			results = append(results, hostResults...)
+			mu.Unlock()
+		}(hostSpec)
+	}
+
+	wg.Wait()
+	return results
+}
+
+func runHost(hostSpec string, tasks []Task, opts RemoteOptions, logger *Logger) []TaskResult {
+	label := hostLabel(hostSpec)
+
+	user, addr, err := parseHostAddr(hostSpec)
+	if err != nil {
+		logger.Error(fmt.Sprintf("[%s] %v", label, err))
+		return []TaskResult{{Host: label, Label: "(connect)", Status: StatusFail, Error: err.Error()}}
+	}
+
+	cfg, err := sshClientConfig(user, label, logger)
+	if err != nil {
+		logger.Error(fmt.Sprintf("[%s] %v", label, err))
+		return []TaskResult{{Host: label, Label: "(connect)", Status: StatusFail, Error: err.Error()}}
+	}
+
+	client, err := ssh.Dial("tcp", addr, cfg)
+	if err != nil {
+		logger.Error(fmt.Sprintf("[%s] connecting: %v", label, err))
+		return []TaskResult{{Host: label, Label: "(connect)", Status: StatusFail, Error: err.Error()}}
+	}
+	defer client.Close()
+
+	var results []TaskResult
+	for _, task := range tasks {
+		if opts.FailFast && hasFailure(results) {
+			results = append(results, TaskResult{Host: label, Label: task.Label, Status: StatusSkipped})
+			continue
+		}
+		results = append(results, runRemoteTask(client, label, task, opts, logger))
+	}
+	return results
+}
+
+func hasFailure(results []TaskResult) bool {
+	for _, r := range results {
+		if r.Status == StatusFail || r.Status == StatusTimeout {
+			return true
+		}
+	}
+	return false
+}
+
+func hostLabel(spec string) string {
+	if i := strings.IndexByte(spec, '@'); i >= 0 {
+		return spec[i+1:]
+	}
+	return spec
+}
+
+// parseHostAddr splits "user@host[:port]" into the SSH user and a
+// host:port suitable for net.Dial, defaulting to port 22.
+func parseHostAddr(spec string) (user, addr string, err error) {
+	parts := strings.SplitN(spec, "@", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid --host entry %q, want user@server", spec)
+	}
+	user, host := parts[0], parts[1]
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, "22")
+	}
+	return user, host, nil
+}
+
+// sshClientConfig builds an authenticated ssh.ClientConfig for user, trying
+// a running ssh-agent first and falling back to unencrypted keys under
+// ~/.ssh/id_*.
+func sshClientConfig(user, label string, logger *Logger) (*ssh.ClientConfig, error) {
+	var methods []ssh.AuthMethod
+
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if conn, err := net.Dial("unix", sock); err == nil {
+			methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+		}
+	}
+
+	if home, err := os.UserHomeDir(); err == nil {
+		for _, name := range []string{"id_ed25519", "id_rsa", "id_ecdsa"} {
+			b, err := os.ReadFile(filepath.Join(home, ".ssh", name))
+			if err != nil {
+				continue
+			}
+			signer, err := ssh.ParsePrivateKey(b)
+			if err != nil {
+				// Likely passphrase-protected; an agent is the supported path for those.
+				continue
+			}
+			methods = append(methods, ssh.PublicKeys(signer))
+		}
+	}
+
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("no usable SSH credentials for %q (start ssh-agent or add an unencrypted key under ~/.ssh)", user)
+	}
+
+	return &ssh.ClientConfig{
+		User:            user,
+		Auth:            methods,
+		HostKeyCallback: hostKeyCallback(label, logger),
+		Timeout:         10 * time.Second,
+	}, nil
+}
+
+// hostKeyCallback verifies against ~/.ssh/known_hosts when available. Without
+// one, it falls back to accepting any key, with no trust-on-first-use
+// persistence (unlike the system `ssh` client, which prompts and records the
+// key) — callers are warned so this doesn't happen silently.
+func hostKeyCallback(label string, logger *Logger) ssh.HostKeyCallback {
+	if home, err := os.UserHomeDir(); err == nil {
+		if cb, err := knownhosts.New(filepath.Join(home, ".ssh", "known_hosts")); err == nil {
+			return cb
+		}
+	}
+	logger.Warn(fmt.Sprintf("[%s] no usable ~/.ssh/known_hosts, accepting the host key without verification", label))
+	return ssh.InsecureIgnoreHostKey()
+}
+
+func runRemoteTask(client *ssh.Client, host string, task Task, opts RemoteOptions, logger *Logger) TaskResult {
+	full := task.FullCommand()
+	res := TaskResult{Host: host, Label: task.Label, Command: strings.Join(full, " "), Start: time.Now()}
+	eventName := fmt.Sprintf("%s/%s", host, task.Label)
+	logger.TaskEvent(eventName, "start")
+
+	session, err := client.NewSession()
+	if err != nil {
+		res.End = time.Now()
+		res.Status = StatusFail
+		res.Error = fmt.Errorf("opening SSH session: %w", err).Error()
+		logger.TaskEvent(eventName, strings.ToLower(string(res.Status)))
+		return res
+	}
+	defer session.Close()
+
+	remotePath := opts.RemotePath
+	if remotePath == "" {
+		remotePath = "."
+	}
+	if task.Cwd != "" {
+		remotePath = filepath.Join(remotePath, task.Cwd)
+	}
+
+	remoteCmd := fmt.Sprintf("cd %s && %s", shellQuote(remotePath), strings.Join(full, " "))
+	for k, v := range task.Env {
+		remoteCmd = fmt.Sprintf("%s=%s %s", k, shellQuote(v), remoteCmd)
+	}
+
+	var buf bytes.Buffer
+	prefixed := newPrefixWriter(os.Stdout, fmt.Sprintf("[%s] ", host))
+	out := io.MultiWriter(prefixed, &buf)
+	session.Stdout = out
+	session.Stderr = out
+
+	done := make(chan error, 1)
+	go func() { done <- session.Run(remoteCmd) }()
+
+	var runErr error
+	timedOut := false
+	if opts.Timeout > 0 {
+		select {
+		case runErr = <-done:
+		case <-time.After(opts.Timeout):
+			timedOut = true
+			_ = session.Signal(ssh.SIGKILL)
+			_ = session.Close()
+			<-done
+		}
+	} else {
+		runErr = <-done
+	}
+
+	prefixed.Flush()
+	res.End = time.Now()
+	res.Duration = res.End.Sub(res.Start)
+	res.Output = buf.String()
+
+	switch {
+	case timedOut:
+		res.Status = StatusTimeout
+		res.ExitCode = -1
+		res.Error = fmt.Sprintf("timed out after %s", opts.Timeout)
+	case runErr == nil:
+		res.Status = StatusPass
+	default:
+		res.Status = StatusFail
+		res.Error = runErr.Error()
+		if exitErr, ok := runErr.(*ssh.ExitError); ok {
+			res.ExitCode = exitErr.ExitStatus()
+		} else {
+			res.ExitCode = -1
+		}
+	}
+
+	logger.TaskEvent(eventName, strings.ToLower(string(res.Status)))
+	return res
+}
+
+// shellQuote wraps s in single quotes for safe inclusion in a remote shell
+// command line.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
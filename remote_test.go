@@ -0,0 +1,70 @@
+package main
+
+import "testing"
+
+func TestParseHostsFlag(t *testing.T) {
+	got := parseHostsFlag(" deploy@web-1 , deploy@web-2,, ")
+	want := []string{"deploy@web-1", "deploy@web-2"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
+
+func TestParseHostAddr(t *testing.T) {
+	cases := []struct {
+		spec    string
+		user    string
+		addr    string
+		wantErr bool
+	}{
+		{spec: "deploy@web-1", user: "deploy", addr: "web-1:22"},
+		{spec: "deploy@web-1:2222", user: "deploy", addr: "web-1:2222"},
+		{spec: "web-1", wantErr: true},
+		{spec: "@web-1", wantErr: true},
+		{spec: "deploy@", wantErr: true},
+	}
+
+	for _, c := range cases {
+		user, addr, err := parseHostAddr(c.spec)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("parseHostAddr(%q): expected error, got none", c.spec)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseHostAddr(%q): unexpected error: %v", c.spec, err)
+			continue
+		}
+		if user != c.user || addr != c.addr {
+			t.Errorf("parseHostAddr(%q) = (%q, %q), want (%q, %q)", c.spec, user, addr, c.user, c.addr)
+		}
+	}
+}
+
+func TestHostLabel(t *testing.T) {
+	if got := hostLabel("deploy@web-1"); got != "web-1" {
+		t.Errorf("hostLabel(deploy@web-1) = %q, want web-1", got)
+	}
+	if got := hostLabel("web-1"); got != "web-1" {
+		t.Errorf("hostLabel(web-1) = %q, want web-1", got)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	cases := map[string]string{
+		"simple": "'simple'",
+		"it's":   `'it'\''s'`,
+		"":       "''",
+	}
+	for in, want := range cases {
+		if got := shellQuote(in); got != want {
+			t.Errorf("shellQuote(%q) = %q, want %q", in, got, want)
+		}
+	}
+}